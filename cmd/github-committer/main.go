@@ -0,0 +1,229 @@
+// Command github-committer is a thin CLI wrapper over pkg/committer.
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/jessevdk/go-flags"
+
+	"github.com/Harry-Moore-dev/github-commiter/pkg/committer"
+	"github.com/Harry-Moore-dev/github-commiter/pkg/provider"
+)
+
+type Opts struct {
+	Repository  string `short:"r" long:"repository" description:"the repository to push commits to" required:"true"`
+	BranchName  string `short:"b" long:"branch" description:"the branch to push commits to" required:"true"`
+	Message     string `short:"m" long:"message" description:"the commit message to use" default:"updated with github-signer"`
+	PullRequest bool   `short:"p" long:"prmake" description:"automatically raises a pull request if set"`
+
+	Provider   string `long:"provider" description:"forge to push to: github, gitlab, gitea, bitbucket (default: autodetect from the origin remote)"`
+	APIURL     string `long:"api-url" description:"API base URL for self-hosted GitLab/Gitea/Bitbucket Server instances, or a GitHub Enterprise Server GraphQL endpoint"`
+	SigningKey string `long:"signing-key" description:"path to an armored PGP private key, used to sign commits on non-GitHub providers"`
+
+	AppID          int64  `long:"app-id" description:"GitHub App ID; authenticates as the app's installation instead of a GITHUB_TOKEN PAT"`
+	InstallationID int64  `long:"installation-id" description:"GitHub App installation ID to mint an installation token for (required with --app-id)"`
+	AppPrivateKey  string `long:"app-private-key" description:"path to the GitHub App's PEM private key (default: the GITHUB_APP_PRIVATE_KEY env var)"`
+
+	IncludeUntracked bool `long:"include-untracked" description:"also commit untracked files (still honors .gitignore) without needing a prior git add"`
+
+	BaseBranch string   `long:"base-branch" description:"branch new work branches from and pull requests target (default: the repository's default branch)"`
+	PRBody     string   `long:"pr-body" description:"body text for the pull request"`
+	PRDraft    bool     `long:"pr-draft" description:"open the pull request as a draft"`
+	PRLabel    []string `long:"pr-label" description:"label to apply to the pull request (repeatable)"`
+	PRReviewer []string `long:"pr-reviewer" description:"user to request review from (repeatable)"`
+	PRAssignee []string `long:"pr-assignee" description:"user to assign to the pull request (repeatable)"`
+
+	MaxRetries int  `long:"max-retries" description:"retries on a stale expected-head-oid conflict before giving up" default:"3"`
+	DryRun     bool `long:"dry-run" description:"log the operations that would be performed instead of executing them"`
+}
+
+func main() {
+	ctx := context.Background()
+
+	var opts Opts
+	parser := flags.NewParser(&opts, flags.Default)
+	_, err := parser.Parse()
+	switch e := err.(type) {
+	case *flags.Error:
+		if e.Type == flags.ErrHelp {
+			os.Exit(0)
+		} else {
+			os.Exit(1)
+		}
+	case nil:
+		break
+	default:
+		log.Fatal(err)
+	}
+
+	repo, status, err := openRepository()
+	if err != nil {
+		log.Fatalf("unable to open repository: %s", err)
+	}
+	changes, deletions := committer.CollectChanges(log.Default(), repo, status, opts.IncludeUntracked)
+	if len(changes) == 0 && len(deletions) == 0 {
+		log.Printf("no changes to commit, exiting")
+		os.Exit(0)
+	}
+
+	kind := committer.DetectKind(repo, provider.Kind(opts.Provider))
+
+	options := buildOptions(opts, repo, kind)
+	if opts.AppID != 0 {
+		if kind != provider.GitHub {
+			log.Fatalf("--app-id is only supported for the GitHub provider, got --provider %s", opts.Provider)
+		}
+		if opts.InstallationID == 0 {
+			log.Fatalf("--installation-id is required with --app-id")
+		}
+		tokenSource, err := provider.NewAppTokenSource(opts.AppID, opts.InstallationID, opts.AppPrivateKey, opts.APIURL, http.DefaultClient)
+		if err != nil {
+			log.Fatalf("unable to set up GitHub App auth: %s", err)
+		}
+		options = append(options, committer.WithTokenSource(tokenSource))
+	}
+
+	client, err := committer.NewClient(ctx, token(kind), options...)
+	if err != nil {
+		log.Fatalf("unable to set up client: %s", err)
+	}
+
+	baseBranch := opts.BaseBranch
+	if baseBranch == "" {
+		baseBranch, err = client.DefaultBranch(ctx)
+		if err != nil {
+			log.Fatalf("unable to look up default branch: %s", err)
+		}
+	}
+
+	oid, err := client.HeadOID(ctx, opts.BranchName)
+	if err != nil {
+		log.Fatalf("unable to lookup branch: %s", err)
+	}
+
+	var expectedHeadOID string
+	if oid == "" {
+		baseOid, err := client.HeadOID(ctx, baseBranch)
+		if err != nil {
+			log.Fatalf("unable to lookup oid: %s", err)
+		}
+		if err := client.EnsureBranch(ctx, opts.BranchName, baseOid); err != nil {
+			log.Fatalf("unable to create branch: %s", err)
+		}
+		// baseOid, not the local checkout's HEAD, is the tip EnsureBranch
+		// just created opts.BranchName at — the local checkout may be on a
+		// different commit entirely (stale clone, or --base-branch pointing
+		// elsewhere), and signedPush/createCommitOnBranch both need the
+		// parent that's actually on the remote.
+		expectedHeadOID = baseOid
+	} else {
+		if err := fetchRemote(repo); err != nil {
+			log.Printf("unable to fetch remote: %s", err)
+		}
+		refName := plumbing.ReferenceName("refs/remotes/origin/" + opts.BranchName)
+		revision, err := repo.Reference(refName, true)
+		if err != nil {
+			log.Fatalf("unable to find HEAD for branch %s: %s", opts.BranchName, err)
+		}
+		expectedHeadOID = revision.Hash().String()
+	}
+
+	commitReq := committer.CommitRequest{
+		Branch:          opts.BranchName,
+		Message:         opts.Message,
+		Changes:         changes,
+		Deletions:       deletions,
+		ExpectedHeadOID: expectedHeadOID,
+	}
+	_, err = client.CommitWithRetry(ctx, commitReq, opts.MaxRetries, func(expectedHeadOID string) (committer.CommitRequest, error) {
+		log.Printf("branch moved since we last read it, rebuilding changes against %s", expectedHeadOID)
+		_, freshStatus, err := openRepository()
+		if err != nil {
+			return committer.CommitRequest{}, err
+		}
+		freshChanges, freshDeletions := committer.CollectChanges(log.Default(), repo, freshStatus, opts.IncludeUntracked)
+		commitReq.Changes = freshChanges
+		commitReq.Deletions = freshDeletions
+		commitReq.ExpectedHeadOID = expectedHeadOID
+		return commitReq, nil
+	})
+	if err != nil {
+		log.Fatalf("unable to commit: %s", err)
+	}
+
+	if opts.PullRequest {
+		err = client.OpenPullRequest(ctx, committer.PullRequestRequest{
+			Base:      baseBranch,
+			Head:      opts.BranchName,
+			Title:     opts.Message,
+			Body:      opts.PRBody,
+			Draft:     opts.PRDraft,
+			Labels:    opts.PRLabel,
+			Reviewers: opts.PRReviewer,
+			Assignees: opts.PRAssignee,
+		})
+		if err != nil {
+			log.Fatalf("unable to create PR: %s", err)
+		}
+	}
+}
+
+// buildOptions translates the CLI flags into committer.Option values.
+// Each provider still reads its token from its own env var so multiple
+// forge credentials can sit in the environment side by side. kind is
+// already resolved (explicit --provider or autodetected from the origin
+// remote), so it's passed straight to WithKind rather than re-detected.
+func buildOptions(opts Opts, repo *git.Repository, kind provider.Kind) []committer.Option {
+	return []committer.Option{
+		committer.WithRepository(opts.Repository),
+		committer.WithGitRepo(repo),
+		committer.WithKind(kind),
+		committer.WithBaseURL(opts.APIURL),
+		committer.WithSigningKey(opts.SigningKey),
+		committer.WithAuthor("github-committer", "github-committer@users.noreply.github.com"),
+		committer.WithDryRun(opts.DryRun),
+	}
+}
+
+// token picks the env var to read based on the resolved forge kind.
+func token(kind provider.Kind) string {
+	switch kind {
+	case provider.GitLab:
+		return os.Getenv("GITLAB_TOKEN")
+	case provider.Gitea:
+		return os.Getenv("GITEA_TOKEN")
+	case provider.BitbucketServer:
+		return os.Getenv("BITBUCKET_TOKEN")
+	default:
+		return os.Getenv("GITHUB_TOKEN")
+	}
+}
+
+func openRepository() (*git.Repository, git.Status, error) {
+	repo, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, nil, err
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, nil, err
+	}
+	return repo, status, nil
+}
+
+func fetchRemote(repo *git.Repository) error {
+	remote, err := repo.Remote("origin")
+	if err != nil {
+		return err
+	}
+	return remote.Fetch(&git.FetchOptions{})
+}
@@ -0,0 +1,76 @@
+package committer
+
+import (
+	"log"
+	"os"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// CollectChanges turns a worktree's status into the additions and deletions
+// to push. It also detects renames: a deleted path whose old blob hash
+// matches an added path's current content is logged as a rename rather than
+// an unrelated delete+add, though the wire payload is the same either way —
+// neither GitHub's createCommitOnBranch nor a plain git commit has a
+// separate "rename" primitive. logger receives the progress lines; pass
+// log.Default() to match the CLI's previous behavior.
+func CollectChanges(logger *log.Logger, repo *git.Repository, status git.Status, includeUntracked bool) ([]FileChange, []string) {
+	changes, deletions := splitChanges(logger, status, includeUntracked)
+	logRenames(logger, repo, changes, deletions)
+	return changes, deletions
+}
+
+func splitChanges(logger *log.Logger, status git.Status, includeUntracked bool) ([]FileChange, []string) {
+	changes := []FileChange{}
+	deletions := []string{}
+
+	for name, s := range status {
+		switch {
+		case s.Worktree == git.Deleted || s.Staging == git.Deleted:
+			logger.Printf("deleting %s", name)
+			deletions = append(deletions, name)
+		case s.Worktree == git.Modified || s.Staging == git.Added || s.Staging == git.Modified:
+			logger.Printf("adding %s", name)
+			b, _ := os.ReadFile(name)
+			changes = append(changes, FileChange{Path: name, Contents: b})
+		case s.Worktree == git.Untracked && includeUntracked:
+			logger.Printf("adding untracked %s", name)
+			b, _ := os.ReadFile(name)
+			changes = append(changes, FileChange{Path: name, Contents: b})
+		}
+	}
+	return changes, deletions
+}
+
+// logRenames pairs each deletion with an addition carrying identical blob
+// content, purely to produce a friendlier log line — it doesn't change what
+// gets committed.
+func logRenames(logger *log.Logger, repo *git.Repository, changes []FileChange, deletions []string) {
+	head, err := repo.Head()
+	if err != nil {
+		return
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return
+	}
+
+	oldHashes := map[plumbing.Hash]string{}
+	for _, path := range deletions {
+		if entry, err := tree.File(path); err == nil {
+			oldHashes[entry.Hash] = path
+		}
+	}
+
+	for _, change := range changes {
+		hash := plumbing.ComputeHash(plumbing.BlobObject, change.Contents)
+		if oldPath, ok := oldHashes[hash]; ok {
+			logger.Printf("renamed %s -> %s", oldPath, change.Path)
+		}
+	}
+}
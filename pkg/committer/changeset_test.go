@@ -0,0 +1,104 @@
+package committer
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestSplitChanges(t *testing.T) {
+	dir := t.TempDir()
+	cwd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(cwd) })
+
+	write(t, "modified.txt", "modified contents")
+	write(t, "added.txt", "added contents")
+	write(t, "untracked.txt", "untracked contents")
+
+	status := git.Status{
+		"modified.txt":  &git.FileStatus{Worktree: git.Modified},
+		"added.txt":     &git.FileStatus{Staging: git.Added},
+		"untracked.txt": &git.FileStatus{Worktree: git.Untracked},
+		"deleted.txt":   &git.FileStatus{Worktree: git.Deleted},
+		"unrelated.txt": &git.FileStatus{Worktree: git.Unmodified},
+	}
+
+	logger := log.New(os.Stderr, "", 0)
+
+	t.Run("without untracked", func(t *testing.T) {
+		changes, deletions := splitChanges(logger, status, false)
+		assertPaths(t, changes, "added.txt", "modified.txt")
+		assertDeletions(t, deletions, "deleted.txt")
+		assertContents(t, changes, "added.txt", "added contents")
+		assertContents(t, changes, "modified.txt", "modified contents")
+	})
+
+	t.Run("with untracked", func(t *testing.T) {
+		changes, deletions := splitChanges(logger, status, true)
+		assertPaths(t, changes, "added.txt", "modified.txt", "untracked.txt")
+		assertDeletions(t, deletions, "deleted.txt")
+	})
+}
+
+func write(t *testing.T, name, contents string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(".", name), []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertPaths(t *testing.T, changes []FileChange, want ...string) {
+	t.Helper()
+	got := make([]string, 0, len(changes))
+	for _, c := range changes {
+		got = append(got, c.Path)
+	}
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("changes = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("changes = %v, want %v", got, want)
+		}
+	}
+}
+
+func assertDeletions(t *testing.T, deletions []string, want ...string) {
+	t.Helper()
+	got := append([]string(nil), deletions...)
+	sort.Strings(got)
+	sort.Strings(want)
+	if len(got) != len(want) {
+		t.Fatalf("deletions = %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("deletions = %v, want %v", got, want)
+		}
+	}
+}
+
+func assertContents(t *testing.T, changes []FileChange, path, want string) {
+	t.Helper()
+	for _, c := range changes {
+		if c.Path == path {
+			if string(c.Contents) != want {
+				t.Fatalf("contents of %s = %q, want %q", path, c.Contents, want)
+			}
+			return
+		}
+	}
+	t.Fatalf("no change found for %s", path)
+}
@@ -0,0 +1,263 @@
+// Package committer exposes github-committer as a library: a typed Client
+// wrapping pkg/provider, so callers can push signed commits and open pull
+// requests without going through the CLI. cmd/github-committer is a thin
+// wrapper over this package.
+package committer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/go-git/go-git/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/Harry-Moore-dev/github-commiter/pkg/provider"
+)
+
+// FileChange, CommitRequest, CommitResult and PullRequestRequest are the same
+// shapes pkg/provider uses; they're aliased here so library callers don't
+// need to import pkg/provider directly for routine use.
+type (
+	FileChange         = provider.FileChange
+	CommitRequest      = provider.CommitRequest
+	CommitResult       = provider.CommitResult
+	PullRequestRequest = provider.PullRequestRequest
+)
+
+// Client is a typed handle to a single forge repository. Construct one with
+// NewClient.
+type Client struct {
+	prov   provider.Provider
+	repo   *git.Repository
+	logger *log.Logger
+	dryRun bool
+}
+
+type config struct {
+	kind           provider.Kind
+	repository     string
+	baseURL        string
+	tokenSource    oauth2.TokenSource
+	gitRepo        *git.Repository
+	signingKeyPath string
+	authorName     string
+	authorEmail    string
+	httpClient     *http.Client
+	logger         *log.Logger
+	dryRun         bool
+}
+
+// Option configures a Client constructed by NewClient.
+type Option func(*config)
+
+// WithRepository sets the "owner/name" repository to operate on. Required.
+func WithRepository(repository string) Option {
+	return func(c *config) { c.repository = repository }
+}
+
+// WithKind pins the forge kind (GitHub, GitLab, Gitea, Bitbucket Server)
+// instead of autodetecting it from the "origin" remote.
+func WithKind(kind provider.Kind) Option {
+	return func(c *config) { c.kind = kind }
+}
+
+// WithBaseURL points the client at a self-hosted GitLab/Gitea/Bitbucket
+// Server instance, or a GitHub Enterprise Server instance's GraphQL endpoint.
+func WithBaseURL(baseURL string) Option {
+	return func(c *config) { c.baseURL = baseURL }
+}
+
+// WithTokenSource authenticates the GitHub provider with an
+// oauth2.TokenSource instead of the static token passed to NewClient, e.g.
+// one from provider.NewAppTokenSource for GitHub App installation auth. It
+// takes priority over the NewClient token when set; the REST-based
+// providers (GitLab, Gitea, Bitbucket Server) don't consult it.
+func WithTokenSource(src oauth2.TokenSource) Option {
+	return func(c *config) { c.tokenSource = src }
+}
+
+// WithGitRepo supplies an already-open local repository instead of opening
+// "." on construction.
+func WithGitRepo(repo *git.Repository) Option {
+	return func(c *config) { c.gitRepo = repo }
+}
+
+// WithSigningKey sets the path to an armored PGP private key, used to sign
+// commits on providers that fall back to a local go-git push.
+func WithSigningKey(path string) Option {
+	return func(c *config) { c.signingKeyPath = path }
+}
+
+// WithAuthor sets the name and email recorded on locally signed commits.
+func WithAuthor(name, email string) Option {
+	return func(c *config) { c.authorName, c.authorEmail = name, email }
+}
+
+// WithHTTPClient overrides the http.Client used to talk to the forge; the
+// GitHub provider wraps it in its OAuth2 transport. Defaults to
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *config) { c.httpClient = httpClient }
+}
+
+// WithLogger overrides where the client's progress lines go. Defaults to
+// log.Default().
+func WithLogger(logger *log.Logger) Option {
+	return func(c *config) { c.logger = logger }
+}
+
+// WithDryRun makes Commit, EnsureBranch and OpenPullRequest log the
+// operation they would have performed instead of calling the forge.
+func WithDryRun(dryRun bool) Option {
+	return func(c *config) { c.dryRun = dryRun }
+}
+
+// DetectKind resolves the forge kind a Client built with WithRepository on
+// repo and opt would use: explicit if it's non-empty (what WithKind sets),
+// otherwise whatever can be detected from repo's "origin" remote, falling
+// back to GitHub. It's exposed so callers that need the kind ahead of
+// NewClient — e.g. to pick which token env var to read — resolve it the
+// same way NewClient does internally, rather than duplicating (and
+// potentially drifting from) that logic.
+func DetectKind(repo *git.Repository, explicit provider.Kind) provider.Kind {
+	return detectKind(repo, explicit)
+}
+
+func detectKind(repo *git.Repository, explicit provider.Kind) provider.Kind {
+	if explicit != "" {
+		return explicit
+	}
+	if remote, err := repo.Remote("origin"); err == nil && len(remote.Config().URLs) > 0 {
+		return provider.DetectKind(remote.Config().URLs[0])
+	}
+	return provider.GitHub
+}
+
+// NewClient builds a Client authenticated with token. WithRepository is
+// required; the forge kind is autodetected from the "origin" remote unless
+// WithKind overrides it.
+func NewClient(ctx context.Context, token string, opts ...Option) (*Client, error) {
+	cfg := config{
+		authorName:  "github-committer",
+		authorEmail: "github-committer@users.noreply.github.com",
+		logger:      log.Default(),
+		httpClient:  http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	if cfg.repository == "" {
+		return nil, fmt.Errorf("committer: WithRepository is required")
+	}
+
+	if cfg.gitRepo == nil {
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			return nil, fmt.Errorf("opening repository: %w", err)
+		}
+		cfg.gitRepo = repo
+	}
+
+	cfg.kind = detectKind(cfg.gitRepo, cfg.kind)
+
+	prov, err := provider.New(ctx, provider.Config{
+		Kind:           cfg.kind,
+		Repository:     cfg.repository,
+		Token:          token,
+		BaseURL:        cfg.baseURL,
+		TokenSource:    cfg.tokenSource,
+		GitRepo:        cfg.gitRepo,
+		SigningKeyPath: cfg.signingKeyPath,
+		AuthorName:     cfg.authorName,
+		AuthorEmail:    cfg.authorEmail,
+		HTTPClient:     cfg.httpClient,
+		Logger:         cfg.logger,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{prov: prov, repo: cfg.gitRepo, logger: cfg.logger, dryRun: cfg.dryRun}, nil
+}
+
+// HeadOID returns the current commit OID of branch, or "" if it doesn't
+// exist.
+func (c *Client) HeadOID(ctx context.Context, branch string) (string, error) {
+	return c.prov.HeadOID(ctx, branch)
+}
+
+// DefaultBranch returns the repository's configured default branch.
+func (c *Client) DefaultBranch(ctx context.Context) (string, error) {
+	return c.prov.DefaultBranch(ctx)
+}
+
+// EnsureBranch creates branch pointing at oid if it doesn't already exist.
+// In dry-run mode it only logs the branch it would create.
+func (c *Client) EnsureBranch(ctx context.Context, branch string, oid string) error {
+	if c.dryRun {
+		c.logger.Printf("dry run: would ensure branch %s exists at %s", branch, oid)
+		return nil
+	}
+	return c.prov.EnsureBranch(ctx, branch, oid)
+}
+
+// Commit creates a new commit on req.Branch. In dry-run mode it logs the
+// mutation it would have sent instead of calling the forge.
+func (c *Client) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	if c.dryRun {
+		c.logDryRunCommit(req)
+		return CommitResult{}, nil
+	}
+	return c.prov.Commit(ctx, req)
+}
+
+// CommitWithRetry behaves like Commit, but on provider.ErrStaleHead asks
+// rebuild for a fresh CommitRequest against the branch's new tip and retries,
+// per provider.RetryCommit. It's a no-op wrapper around Commit in dry-run
+// mode, since there's nothing to conflict with.
+func (c *Client) CommitWithRetry(ctx context.Context, req CommitRequest, maxRetries int, rebuild func(expectedHeadOID string) (CommitRequest, error)) (CommitResult, error) {
+	if c.dryRun {
+		return c.Commit(ctx, req)
+	}
+	return provider.RetryCommit(ctx, c.prov, req, maxRetries, rebuild)
+}
+
+// OpenPullRequest opens a pull request for req.Head against req.Base, or
+// updates the existing open one. In dry-run mode it only logs the request.
+func (c *Client) OpenPullRequest(ctx context.Context, req PullRequestRequest) error {
+	if c.dryRun {
+		c.logger.Printf("dry run: would open pull request %s -> %s: %q", req.Head, req.Base, req.Title)
+		return nil
+	}
+	return c.prov.OpenPullRequest(ctx, req)
+}
+
+// Status reports the pending file additions and modifications in the
+// client's git worktree, honoring .gitignore. It does not report deletions;
+// use CollectChanges directly if a full CommitRequest, deletions included,
+// needs to be built from the worktree.
+func (c *Client) Status(ctx context.Context) ([]FileChange, error) {
+	worktree, err := c.repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := worktree.Status()
+	if err != nil {
+		return nil, err
+	}
+	changes, _ := splitChanges(c.logger, status, false)
+	return changes, nil
+}
+
+func (c *Client) logDryRunCommit(req CommitRequest) {
+	c.logger.Printf("dry run: would commit to %s (expected head %s): %q", req.Branch, req.ExpectedHeadOID, req.Message)
+	for _, change := range req.Changes {
+		c.logger.Printf("dry run:   add %s (%d bytes)", change.Path, len(change.Contents))
+	}
+	for _, path := range req.Deletions {
+		c.logger.Printf("dry run:   delete %s", path)
+	}
+}
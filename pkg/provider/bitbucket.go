@@ -0,0 +1,178 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// bitbucketProvider talks to the Bitbucket Server (Data Center) REST API.
+// Unlike GitHub/GitLab/Gitea, Bitbucket Server has no public SaaS
+// equivalent here: BaseURL is required, since every installation is
+// self-hosted.
+type bitbucketProvider struct {
+	cfg     Config
+	http    *http.Client
+	baseURL string
+	project string
+	repo    string
+}
+
+func newBitbucketProvider(ctx context.Context, cfg Config) (Provider, error) {
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("bitbucket provider requires --api-url pointing at the Bitbucket Server instance")
+	}
+	parts := strings.SplitN(cfg.Repository, "/", 2)
+	return &bitbucketProvider{
+		cfg:     cfg,
+		http:    cfg.httpClient(),
+		baseURL: strings.TrimRight(cfg.BaseURL, "/"),
+		project: parts[0],
+		repo:    parts[1],
+	}, nil
+}
+
+func (p *bitbucketProvider) HeadOID(ctx context.Context, branch string) (string, error) {
+	var out struct {
+		Values []struct {
+			DisplayID    string `json:"displayId"`
+			LatestCommit string `json:"latestCommit"`
+		} `json:"values"`
+	}
+	path := fmt.Sprintf("/projects/%s/repos/%s/branches?filterText=%s", p.project, p.repo, branch)
+	if err := p.get(ctx, path, &out); err != nil {
+		return "", err
+	}
+	for _, b := range out.Values {
+		if b.DisplayID == branch {
+			return b.LatestCommit, nil
+		}
+	}
+	return "", nil
+}
+
+func (p *bitbucketProvider) DefaultBranch(ctx context.Context) (string, error) {
+	var out struct {
+		DisplayID string `json:"displayId"`
+	}
+	err := p.get(ctx, fmt.Sprintf("/projects/%s/repos/%s/branches/default", p.project, p.repo), &out)
+	if err != nil {
+		return "", err
+	}
+	return out.DisplayID, nil
+}
+
+func (p *bitbucketProvider) EnsureBranch(ctx context.Context, branch string, oid string) error {
+	existing, err := p.HeadOID(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if existing != "" {
+		return nil
+	}
+
+	body := map[string]string{"name": branch, "startPoint": oid}
+	err = p.post(ctx, fmt.Sprintf("/projects/%s/repos/%s/branches", p.project, p.repo), body, nil)
+	if err != nil {
+		return err
+	}
+	p.cfg.logger().Printf("%s branch created\n", branch)
+	return nil
+}
+
+func (p *bitbucketProvider) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	oid, err := signedPush(p.cfg.GitRepo, p.cfg, req.Branch, req.Message, req.ExpectedHeadOID, req.Changes, req.Deletions)
+	if err != nil {
+		return CommitResult{}, err
+	}
+	return CommitResult{
+		OID: oid,
+		URL: fmt.Sprintf("%s/projects/%s/repos/%s/commits/%s", p.baseURL, p.project, p.repo, oid),
+	}, nil
+}
+
+func (p *bitbucketProvider) OpenPullRequest(ctx context.Context, req PullRequestRequest) error {
+	existing, version, err := p.findPullRequest(ctx, req.Head)
+	if err != nil {
+		return err
+	}
+
+	reviewers := make([]map[string]interface{}, 0, len(req.Reviewers))
+	for _, name := range req.Reviewers {
+		reviewers = append(reviewers, map[string]interface{}{"user": map[string]string{"name": name}})
+	}
+
+	if existing != 0 {
+		// Bitbucket Server uses optimistic locking on pull requests: an
+		// update must echo back the version it read, or the API rejects it
+		// as a conflict.
+		body := map[string]interface{}{
+			"version":     version,
+			"title":       req.Title,
+			"description": req.Body,
+			"toRef":       map[string]string{"id": "refs/heads/" + req.Base},
+			"reviewers":   reviewers,
+		}
+		err = p.put(ctx, fmt.Sprintf("/projects/%s/repos/%s/pull-requests/%d", p.project, p.repo, existing), body, nil)
+		if err != nil {
+			return err
+		}
+		p.cfg.logger().Printf("pull request #%d updated for %s\n", existing, req.Head)
+		return nil
+	}
+
+	body := map[string]interface{}{
+		"title":       req.Title,
+		"description": req.Body,
+		"fromRef":     map[string]string{"id": "refs/heads/" + req.Head},
+		"toRef":       map[string]string{"id": "refs/heads/" + req.Base},
+		"reviewers":   reviewers,
+	}
+	err = p.post(ctx, fmt.Sprintf("/projects/%s/repos/%s/pull-requests", p.project, p.repo), body, nil)
+	if err != nil {
+		return err
+	}
+	p.cfg.logger().Printf("pull request created %s\n", req.Head)
+	return nil
+}
+
+// findPullRequest returns the ID and current version of the open pull
+// request for head, or (0, 0) if there isn't one. version must be echoed
+// back on update per Bitbucket Server's optimistic locking.
+func (p *bitbucketProvider) findPullRequest(ctx context.Context, head string) (id int, version int, err error) {
+	var out struct {
+		Values []struct {
+			ID      int `json:"id"`
+			Version int `json:"version"`
+			FromRef struct {
+				DisplayID string `json:"displayId"`
+			} `json:"fromRef"`
+		} `json:"values"`
+	}
+	if err := p.get(ctx, fmt.Sprintf("/projects/%s/repos/%s/pull-requests?state=OPEN", p.project, p.repo), &out); err != nil {
+		return 0, 0, err
+	}
+	for _, pr := range out.Values {
+		if pr.FromRef.DisplayID == head {
+			return pr.ID, pr.Version, nil
+		}
+	}
+	return 0, 0, nil
+}
+
+func (p *bitbucketProvider) get(ctx context.Context, path string, out interface{}) error {
+	return p.do(ctx, http.MethodGet, path, nil, out)
+}
+
+func (p *bitbucketProvider) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return p.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (p *bitbucketProvider) put(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return p.do(ctx, http.MethodPut, path, body, out)
+}
+
+func (p *bitbucketProvider) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	return restDo(ctx, p.http, method, p.baseURL+"/rest/api/1.0"+path, "Authorization", "Bearer "+p.cfg.Token, body, out)
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const defaultGiteaBaseURL = "https://gitea.com"
+
+// giteaProvider talks to the Gitea REST API for branch lookups and pull
+// requests, and falls back to a locally GPG-signed go-git push for commits,
+// the same as gitlabProvider.
+type giteaProvider struct {
+	cfg     Config
+	http    *http.Client
+	baseURL string
+	owner   string
+	repo    string
+}
+
+func newGiteaProvider(ctx context.Context, cfg Config) (Provider, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGiteaBaseURL
+	}
+	parts := strings.SplitN(cfg.Repository, "/", 2)
+	return &giteaProvider{
+		cfg:     cfg,
+		http:    cfg.httpClient(),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		owner:   parts[0],
+		repo:    parts[1],
+	}, nil
+}
+
+func (p *giteaProvider) HeadOID(ctx context.Context, branch string) (string, error) {
+	var out struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	err := p.get(ctx, fmt.Sprintf("/repos/%s/%s/branches/%s", p.owner, p.repo, branch), &out)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return out.Commit.ID, nil
+}
+
+func (p *giteaProvider) DefaultBranch(ctx context.Context) (string, error) {
+	var out struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.get(ctx, fmt.Sprintf("/repos/%s/%s", p.owner, p.repo), &out); err != nil {
+		return "", err
+	}
+	return out.DefaultBranch, nil
+}
+
+func (p *giteaProvider) EnsureBranch(ctx context.Context, branch string, oid string) error {
+	existing, err := p.HeadOID(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if existing != "" {
+		return nil
+	}
+
+	body := map[string]string{"new_branch_name": branch, "old_ref_name": oid}
+	err = p.post(ctx, fmt.Sprintf("/repos/%s/%s/branches", p.owner, p.repo), body, nil)
+	if err != nil {
+		return err
+	}
+	p.cfg.logger().Printf("%s branch created\n", branch)
+	return nil
+}
+
+func (p *giteaProvider) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	oid, err := signedPush(p.cfg.GitRepo, p.cfg, req.Branch, req.Message, req.ExpectedHeadOID, req.Changes, req.Deletions)
+	if err != nil {
+		return CommitResult{}, err
+	}
+	return CommitResult{
+		OID: oid,
+		URL: fmt.Sprintf("%s/%s/%s/commit/%s", p.baseURL, p.owner, p.repo, oid),
+	}, nil
+}
+
+func (p *giteaProvider) OpenPullRequest(ctx context.Context, req PullRequestRequest) error {
+	existing, err := p.findPullRequest(ctx, req.Head)
+	if err != nil {
+		return err
+	}
+
+	if existing != 0 {
+		body := map[string]interface{}{
+			"base":      req.Base,
+			"title":     req.Title,
+			"body":      req.Body,
+			"assignees": req.Assignees,
+			"reviewers": req.Reviewers,
+		}
+		err = p.patch(ctx, fmt.Sprintf("/repos/%s/%s/pulls/%d", p.owner, p.repo, existing), body, nil)
+		if err != nil {
+			return err
+		}
+		p.cfg.logger().Printf("pull request #%d updated for %s\n", existing, req.Head)
+		return nil
+	}
+
+	body := map[string]interface{}{
+		"base":      req.Base,
+		"head":      req.Head,
+		"title":     req.Title,
+		"body":      req.Body,
+		"assignees": req.Assignees,
+		"reviewers": req.Reviewers,
+	}
+	err = p.post(ctx, fmt.Sprintf("/repos/%s/%s/pulls", p.owner, p.repo), body, nil)
+	if err != nil {
+		return err
+	}
+	p.cfg.logger().Printf("pull request created %s\n", req.Head)
+	return nil
+}
+
+func (p *giteaProvider) findPullRequest(ctx context.Context, head string) (int, error) {
+	var out []struct {
+		Number int `json:"number"`
+		Head   struct {
+			Ref string `json:"ref"`
+		} `json:"head"`
+	}
+	err := p.get(ctx, fmt.Sprintf("/repos/%s/%s/pulls?state=open", p.owner, p.repo), &out)
+	if err != nil {
+		return 0, err
+	}
+	for _, pr := range out {
+		if pr.Head.Ref == head {
+			return pr.Number, nil
+		}
+	}
+	return 0, nil
+}
+
+func (p *giteaProvider) get(ctx context.Context, path string, out interface{}) error {
+	return p.do(ctx, http.MethodGet, path, nil, out)
+}
+
+func (p *giteaProvider) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return p.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (p *giteaProvider) patch(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return p.do(ctx, http.MethodPatch, path, body, out)
+}
+
+func (p *giteaProvider) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	return restDo(ctx, p.http, method, p.baseURL+"/api/v1"+path, "Authorization", "token "+p.cfg.Token, body, out)
+}
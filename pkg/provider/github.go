@@ -0,0 +1,347 @@
+package provider
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// githubProvider talks to GitHub's GraphQL API. It's the only Provider that
+// can produce server-side verified ("Verified") commits without a local GPG
+// key, since createCommitOnBranch signs on GitHub's behalf.
+type githubProvider struct {
+	client *githubv4.Client
+	logger *log.Logger
+	owner  string
+	name   string
+	repoID githubv4.ID
+}
+
+func newGitHubProvider(ctx context.Context, cfg Config) (Provider, error) {
+	src := cfg.TokenSource
+	if src == nil {
+		src = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: cfg.Token})
+	}
+	httpClient := oauth2.NewClient(context.WithValue(ctx, oauth2.HTTPClient, cfg.httpClient()), src)
+
+	var client *githubv4.Client
+	if cfg.BaseURL != "" {
+		client = githubv4.NewEnterpriseClient(cfg.BaseURL, httpClient)
+	} else {
+		client = githubv4.NewClient(httpClient)
+	}
+
+	parts := strings.SplitN(cfg.Repository, "/", 2)
+	return &githubProvider{client: client, logger: cfg.logger(), owner: parts[0], name: parts[1]}, nil
+}
+
+func (p *githubProvider) HeadOID(ctx context.Context, branch string) (string, error) {
+	var query struct {
+		Repository struct {
+			ID  githubv4.ID
+			Ref struct {
+				Target struct {
+					Oid githubv4.GitObjectID
+				}
+			} `graphql:"ref(qualifiedName: $branchName)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner":      githubv4.String(p.owner),
+		"name":       githubv4.String(p.name),
+		"branchName": githubv4.String("refs/heads/" + branch),
+	}
+
+	err := p.client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", err
+	}
+	p.repoID = query.Repository.ID
+	return string(query.Repository.Ref.Target.Oid), nil
+}
+
+func (p *githubProvider) DefaultBranch(ctx context.Context) (string, error) {
+	var query struct {
+		Repository struct {
+			ID               githubv4.ID
+			DefaultBranchRef struct {
+				Name string
+			}
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(p.owner),
+		"name":  githubv4.String(p.name),
+	}
+
+	err := p.client.Query(ctx, &query, variables)
+	if err != nil {
+		return "", err
+	}
+	p.repoID = query.Repository.ID
+	return query.Repository.DefaultBranchRef.Name, nil
+}
+
+func (p *githubProvider) EnsureBranch(ctx context.Context, branch string, oid string) error {
+	existing, err := p.HeadOID(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if existing != "" {
+		return nil
+	}
+
+	var mutation struct {
+		CreateRef struct {
+			ClientMutationID githubv4.String
+		} `graphql:"createRef(input: $input)"`
+	}
+	input := githubv4.CreateRefInput{
+		RepositoryID: p.repoID,
+		Name:         githubv4.String("refs/heads/" + branch),
+		Oid:          githubv4.GitObjectID(oid),
+	}
+
+	err = p.client.Mutate(ctx, &mutation, input, nil)
+	if err != nil {
+		return err
+	}
+	p.logger.Printf("%s branch created\n", branch)
+	return nil
+}
+
+func (p *githubProvider) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	additions := make([]githubv4.FileAddition, 0, len(req.Changes))
+	for _, change := range req.Changes {
+		additions = append(additions, githubv4.FileAddition{
+			Path:     githubv4.String(change.Path),
+			Contents: githubv4.Base64String(base64.StdEncoding.EncodeToString(change.Contents)),
+		})
+	}
+	deletions := make([]githubv4.FileDeletion, 0, len(req.Deletions))
+	for _, path := range req.Deletions {
+		deletions = append(deletions, githubv4.FileDeletion{Path: githubv4.String(path)})
+	}
+
+	var mutation struct {
+		CreateCommitOnBranch struct {
+			Commit struct {
+				Oid githubv4.GitObjectID
+				Url githubv4.ID
+			}
+		} `graphql:"createCommitOnBranch(input: $input)"`
+	}
+	input := githubv4.CreateCommitOnBranchInput{
+		Branch: githubv4.CommittableBranch{
+			RepositoryNameWithOwner: githubv4.NewString(githubv4.String(p.owner + "/" + p.name)),
+			BranchName:              githubv4.NewString(githubv4.String(req.Branch)),
+		},
+		Message: githubv4.CommitMessage{Headline: githubv4.String(req.Message)},
+		FileChanges: &githubv4.FileChanges{
+			Additions: &additions,
+			Deletions: &deletions,
+		},
+		ExpectedHeadOid: githubv4.GitObjectID(req.ExpectedHeadOID),
+	}
+
+	err := p.client.Mutate(ctx, &mutation, input, nil)
+	if err != nil {
+		if isStaleHeadError(err) {
+			return CommitResult{}, fmt.Errorf("%w: %s", ErrStaleHead, err)
+		}
+		return CommitResult{}, err
+	}
+	p.logger.Printf("mutation complete: %s", mutation.CreateCommitOnBranch.Commit.Url)
+	return CommitResult{
+		OID: string(mutation.CreateCommitOnBranch.Commit.Oid),
+		URL: fmt.Sprint(mutation.CreateCommitOnBranch.Commit.Url),
+	}, nil
+}
+
+func (p *githubProvider) OpenPullRequest(ctx context.Context, req PullRequestRequest) error {
+	prID, err := p.findOpenPullRequest(ctx, req.Head)
+	if err != nil {
+		return err
+	}
+
+	if prID != "" {
+		var mutation struct {
+			UpdatePullRequest struct {
+				PullRequest struct {
+					ID githubv4.ID
+				}
+			} `graphql:"updatePullRequest(input: $input)"`
+		}
+		input := githubv4.UpdatePullRequestInput{
+			PullRequestID: prID,
+			BaseRefName:   githubv4.NewString(githubv4.String(req.Base)),
+			Title:         githubv4.NewString(githubv4.String(req.Title)),
+			Body:          githubv4.NewString(githubv4.String(req.Body)),
+		}
+		if err := p.client.Mutate(ctx, &mutation, input, nil); err != nil {
+			return err
+		}
+		p.logger.Printf("pull request updated %s\n", req.Head)
+	} else {
+		var mutation struct {
+			CreatePullRequest struct {
+				PullRequest struct {
+					ID githubv4.ID
+				}
+			} `graphql:"createPullRequest(input: $input)"`
+		}
+		input := githubv4.CreatePullRequestInput{
+			RepositoryID: p.repoID,
+			BaseRefName:  githubv4.String(req.Base),
+			HeadRefName:  githubv4.String(req.Head),
+			Title:        githubv4.String(req.Title),
+			Body:         githubv4.NewString(githubv4.String(req.Body)),
+			Draft:        githubv4.NewBoolean(githubv4.Boolean(req.Draft)),
+		}
+		if err := p.client.Mutate(ctx, &mutation, input, nil); err != nil {
+			return err
+		}
+		prID = mutation.CreatePullRequest.PullRequest.ID
+		p.logger.Printf("pull request created %s\n", req.Head)
+	}
+
+	return p.applyPullRequestMetadata(ctx, prID, req)
+}
+
+// findOpenPullRequest returns the node ID of the open pull request for
+// head, or "" if there isn't one.
+func (p *githubProvider) findOpenPullRequest(ctx context.Context, head string) (githubv4.ID, error) {
+	var query struct {
+		Repository struct {
+			PullRequests struct {
+				Nodes []struct {
+					ID githubv4.ID
+				}
+			} `graphql:"pullRequests(states: OPEN, headRefName: $head, first: 1)"`
+		} `graphql:"repository(owner: $owner, name: $name)"`
+	}
+	variables := map[string]interface{}{
+		"owner": githubv4.String(p.owner),
+		"name":  githubv4.String(p.name),
+		"head":  githubv4.String(head),
+	}
+
+	err := p.client.Query(ctx, &query, variables)
+	if err != nil {
+		return nil, err
+	}
+	if len(query.Repository.PullRequests.Nodes) == 0 {
+		return "", nil
+	}
+	return query.Repository.PullRequests.Nodes[0].ID, nil
+}
+
+// applyPullRequestMetadata attaches labels, reviewers and assignees, each of
+// which needs its name/login resolved to a node ID before it can be used in
+// a mutation.
+func (p *githubProvider) applyPullRequestMetadata(ctx context.Context, prID githubv4.ID, req PullRequestRequest) error {
+	if len(req.Labels) > 0 {
+		labelIDs, err := p.resolveLabelIDs(ctx, req.Labels)
+		if err != nil {
+			return fmt.Errorf("resolving labels: %w", err)
+		}
+		var mutation struct {
+			AddLabelsToLabelable struct {
+				ClientMutationID githubv4.String
+			} `graphql:"addLabelsToLabelable(input: $input)"`
+		}
+		input := githubv4.AddLabelsToLabelableInput{LabelableID: prID, LabelIDs: labelIDs}
+		if err := p.client.Mutate(ctx, &mutation, input, nil); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Reviewers) > 0 {
+		userIDs, err := p.resolveUserIDs(ctx, req.Reviewers)
+		if err != nil {
+			return fmt.Errorf("resolving reviewers: %w", err)
+		}
+		var mutation struct {
+			RequestReviews struct {
+				ClientMutationID githubv4.String
+			} `graphql:"requestReviews(input: $input)"`
+		}
+		input := githubv4.RequestReviewsInput{PullRequestID: prID, UserIDs: &userIDs}
+		if err := p.client.Mutate(ctx, &mutation, input, nil); err != nil {
+			return err
+		}
+	}
+
+	if len(req.Assignees) > 0 {
+		userIDs, err := p.resolveUserIDs(ctx, req.Assignees)
+		if err != nil {
+			return fmt.Errorf("resolving assignees: %w", err)
+		}
+		var mutation struct {
+			AddAssigneesToAssignable struct {
+				ClientMutationID githubv4.String
+			} `graphql:"addAssigneesToAssignable(input: $input)"`
+		}
+		input := githubv4.AddAssigneesToAssignableInput{AssignableID: prID, AssigneeIDs: userIDs}
+		if err := p.client.Mutate(ctx, &mutation, input, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *githubProvider) resolveLabelIDs(ctx context.Context, names []string) ([]githubv4.ID, error) {
+	ids := make([]githubv4.ID, 0, len(names))
+	for _, name := range names {
+		var query struct {
+			Repository struct {
+				Label struct {
+					ID githubv4.ID
+				} `graphql:"label(name: $name)"`
+			} `graphql:"repository(owner: $owner, name: $name2)"`
+		}
+		variables := map[string]interface{}{
+			"owner": githubv4.String(p.owner),
+			"name2": githubv4.String(p.name),
+			"name":  githubv4.String(name),
+		}
+		if err := p.client.Query(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		ids = append(ids, query.Repository.Label.ID)
+	}
+	return ids, nil
+}
+
+// isStaleHeadError recognizes GitHub's createCommitOnBranch error for a
+// branch that moved since ExpectedHeadOid was read, e.g. "Update is not a
+// fast forward" or "head sha ... does not match".
+func isStaleHeadError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "not a fast forward") ||
+		strings.Contains(msg, "does not match") ||
+		strings.Contains(msg, "expected head oid")
+}
+
+func (p *githubProvider) resolveUserIDs(ctx context.Context, logins []string) ([]githubv4.ID, error) {
+	ids := make([]githubv4.ID, 0, len(logins))
+	for _, login := range logins {
+		var query struct {
+			User struct {
+				ID githubv4.ID
+			} `graphql:"user(login: $login)"`
+		}
+		variables := map[string]interface{}{"login": githubv4.String(login)}
+		if err := p.client.Query(ctx, &query, variables); err != nil {
+			return nil, err
+		}
+		ids = append(ids, query.User.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,110 @@
+package provider
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+// NewAppTokenSource builds an oauth2.TokenSource that authenticates as a
+// GitHub App installation rather than a static PAT: it mints a short-lived
+// RS256 JWT signed with the App's private key, exchanges it for an
+// installation access token, and mints a new one whenever the returned
+// token is about to expire. privateKeyPath may be empty, in which case the
+// PEM is read from the GITHUB_APP_PRIVATE_KEY env var instead.
+//
+// apiBaseURL is the provider's GraphQL endpoint (cfg.BaseURL, empty for
+// github.com); the REST access-token exchange lives alongside it at
+// .../api/v3 rather than .../api/graphql on GitHub Enterprise Server.
+func NewAppTokenSource(appID, installationID int64, privateKeyPath, apiBaseURL string, httpClient *http.Client) (oauth2.TokenSource, error) {
+	pemBytes, err := readAppPrivateKey(privateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(pemBytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing GitHub App private key: %w", err)
+	}
+
+	src := &appInstallationTokenSource{
+		appID:          appID,
+		installationID: installationID,
+		key:            key,
+		restBaseURL:    restAPIBaseURL(apiBaseURL),
+		httpClient:     httpClient,
+	}
+	return oauth2.ReuseTokenSource(nil, src), nil
+}
+
+// readAppPrivateKey reads the App's PEM-encoded private key from path, or
+// from GITHUB_APP_PRIVATE_KEY if path is empty.
+func readAppPrivateKey(path string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+	if pem := os.Getenv("GITHUB_APP_PRIVATE_KEY"); pem != "" {
+		return []byte(pem), nil
+	}
+	return nil, fmt.Errorf("no GitHub App private key: pass --app-private-key or set GITHUB_APP_PRIVATE_KEY")
+}
+
+// restAPIBaseURL derives the REST API base (.../api/v3 on GHES, or
+// api.github.com) from the GraphQL endpoint used elsewhere in this package.
+func restAPIBaseURL(graphqlBaseURL string) string {
+	if graphqlBaseURL == "" {
+		return "https://api.github.com"
+	}
+	return strings.Replace(graphqlBaseURL, "/graphql", "/v3", 1)
+}
+
+// appInstallationTokenSource mints a fresh installation access token each
+// time it's asked for one; it's only ever called through
+// oauth2.ReuseTokenSource, which caches the result until it's near expiry.
+type appInstallationTokenSource struct {
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	restBaseURL    string
+	httpClient     *http.Client
+}
+
+func (s *appInstallationTokenSource) Token() (*oauth2.Token, error) {
+	jwtToken, err := s.signedJWT()
+	if err != nil {
+		return nil, fmt.Errorf("signing app JWT: %w", err)
+	}
+
+	var resp struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	url := fmt.Sprintf("%s/app/installations/%d/access_tokens", s.restBaseURL, s.installationID)
+	err = restDo(context.Background(), s.httpClient, http.MethodPost, url, "Authorization", "Bearer "+jwtToken, nil, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("exchanging app JWT for an installation token: %w", err)
+	}
+
+	return &oauth2.Token{AccessToken: resp.Token, Expiry: resp.ExpiresAt}, nil
+}
+
+// signedJWT mints the short-lived JWT GitHub expects for app-level
+// endpoints: iss is the App ID, and iat/exp must both fall within 10
+// minutes of each other. iat is backdated 30s to tolerate clock drift
+// between here and GitHub's servers.
+func (s *appInstallationTokenSource) signedJWT() (string, error) {
+	now := time.Now()
+	claims := jwt.RegisteredClaims{
+		IssuedAt:  jwt.NewNumericDate(now.Add(-30 * time.Second)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(9 * time.Minute)),
+		Issuer:    strconv.FormatInt(s.appID, 10),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.key)
+}
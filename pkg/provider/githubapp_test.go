@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestRestAPIBaseURL(t *testing.T) {
+	cases := map[string]string{
+		"":                                      "https://api.github.com",
+		"https://ghes.example.com/api/graphql":  "https://ghes.example.com/api/v3",
+		"https://ghes.example.com/api/graphql/": "https://ghes.example.com/api/v3/",
+	}
+	for in, want := range cases {
+		if got := restAPIBaseURL(in); got != want {
+			t.Errorf("restAPIBaseURL(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSignedJWTClaims(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	src := &appInstallationTokenSource{appID: 12345, key: key}
+
+	before := time.Now()
+	signed, err := src.signedJWT()
+	if err != nil {
+		t.Fatalf("signedJWT() error = %v", err)
+	}
+	after := time.Now()
+
+	var claims jwt.RegisteredClaims
+	token, err := jwt.ParseWithClaims(signed, &claims, func(tok *jwt.Token) (interface{}, error) {
+		if _, ok := tok.Method.(*jwt.SigningMethodRSA); !ok {
+			t.Fatalf("unexpected signing method %v, want RS256", tok.Header["alg"])
+		}
+		return &key.PublicKey, nil
+	})
+	if err != nil {
+		t.Fatalf("parsing signed JWT: %v", err)
+	}
+	if !token.Valid {
+		t.Fatal("signed JWT did not validate against its own public key")
+	}
+
+	if claims.Issuer != "12345" {
+		t.Errorf("claims.Issuer = %q, want %q", claims.Issuer, "12345")
+	}
+
+	iat := claims.IssuedAt.Time
+	exp := claims.ExpiresAt.Time
+	if iat.After(before.Add(-29 * time.Second)) {
+		t.Errorf("claims.IssuedAt = %s, want roughly 30s before %s", iat, before)
+	}
+	if iat.Before(before.Add(-31 * time.Second)) {
+		t.Errorf("claims.IssuedAt = %s, too far before %s", iat, before)
+	}
+	if exp.Sub(iat) > 10*time.Minute {
+		t.Errorf("exp - iat = %s, want <= 10 minutes", exp.Sub(iat))
+	}
+	if exp.Before(after) {
+		t.Errorf("claims.ExpiresAt = %s, already expired at %s", exp, after)
+	}
+}
@@ -0,0 +1,183 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultGitLabBaseURL = "https://gitlab.com"
+
+// gitlabProvider talks to the GitLab REST API for branch lookups and merge
+// requests, and falls back to a locally GPG-signed go-git push for commits
+// since GitLab has no equivalent of GitHub's createCommitOnBranch.
+type gitlabProvider struct {
+	cfg     Config
+	http    *http.Client
+	baseURL string
+	project string // URL-encoded "owner/name", as the API expects
+}
+
+func newGitLabProvider(ctx context.Context, cfg Config) (Provider, error) {
+	baseURL := cfg.BaseURL
+	if baseURL == "" {
+		baseURL = defaultGitLabBaseURL
+	}
+	return &gitlabProvider{
+		cfg:     cfg,
+		http:    cfg.httpClient(),
+		baseURL: strings.TrimRight(baseURL, "/"),
+		project: url.PathEscape(cfg.Repository),
+	}, nil
+}
+
+func (p *gitlabProvider) HeadOID(ctx context.Context, branch string) (string, error) {
+	var out struct {
+		Commit struct {
+			ID string `json:"id"`
+		} `json:"commit"`
+	}
+	err := p.get(ctx, fmt.Sprintf("/projects/%s/repository/branches/%s", p.project, url.PathEscape(branch)), &out)
+	if isNotFound(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return out.Commit.ID, nil
+}
+
+func (p *gitlabProvider) DefaultBranch(ctx context.Context) (string, error) {
+	var out struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if err := p.get(ctx, fmt.Sprintf("/projects/%s", p.project), &out); err != nil {
+		return "", err
+	}
+	return out.DefaultBranch, nil
+}
+
+func (p *gitlabProvider) EnsureBranch(ctx context.Context, branch string, oid string) error {
+	existing, err := p.HeadOID(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if existing != "" {
+		return nil
+	}
+
+	body := map[string]string{"branch": branch, "ref": oid}
+	err = p.post(ctx, fmt.Sprintf("/projects/%s/repository/branches", p.project), body, nil)
+	if err != nil {
+		return err
+	}
+	p.cfg.logger().Printf("%s branch created\n", branch)
+	return nil
+}
+
+func (p *gitlabProvider) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	oid, err := signedPush(p.cfg.GitRepo, p.cfg, req.Branch, req.Message, req.ExpectedHeadOID, req.Changes, req.Deletions)
+	if err != nil {
+		return CommitResult{}, err
+	}
+	return CommitResult{
+		OID: oid,
+		URL: fmt.Sprintf("%s/%s/-/commit/%s", p.baseURL, p.cfg.Repository, oid),
+	}, nil
+}
+
+func (p *gitlabProvider) OpenPullRequest(ctx context.Context, req PullRequestRequest) error {
+	existing, err := p.findMergeRequest(ctx, req.Head)
+	if err != nil {
+		return err
+	}
+
+	title := req.Title
+	if req.Draft {
+		title = "Draft: " + title
+	}
+	reviewerIDs, err := p.resolveUserIDs(ctx, req.Reviewers)
+	if err != nil {
+		return fmt.Errorf("resolving reviewers: %w", err)
+	}
+	assigneeIDs, err := p.resolveUserIDs(ctx, req.Assignees)
+	if err != nil {
+		return fmt.Errorf("resolving assignees: %w", err)
+	}
+
+	body := map[string]interface{}{
+		"title":        title,
+		"description":  req.Body,
+		"labels":       strings.Join(req.Labels, ","),
+		"reviewer_ids": reviewerIDs,
+		"assignee_ids": assigneeIDs,
+	}
+
+	if existing != 0 {
+		body["target_branch"] = req.Base
+		err = p.put(ctx, fmt.Sprintf("/projects/%s/merge_requests/%d", p.project, existing), body, nil)
+		if err != nil {
+			return err
+		}
+		p.cfg.logger().Printf("merge request !%d updated for %s\n", existing, req.Head)
+		return nil
+	}
+
+	body["source_branch"] = req.Head
+	body["target_branch"] = req.Base
+	err = p.post(ctx, fmt.Sprintf("/projects/%s/merge_requests", p.project), body, nil)
+	if err != nil {
+		return err
+	}
+	p.cfg.logger().Printf("merge request created %s\n", req.Head)
+	return nil
+}
+
+func (p *gitlabProvider) resolveUserIDs(ctx context.Context, usernames []string) ([]int, error) {
+	ids := make([]int, 0, len(usernames))
+	for _, username := range usernames {
+		var out []struct {
+			ID int `json:"id"`
+		}
+		if err := p.get(ctx, "/users?username="+url.QueryEscape(username), &out); err != nil {
+			return nil, err
+		}
+		if len(out) == 0 {
+			return nil, fmt.Errorf("no such user %q", username)
+		}
+		ids = append(ids, out[0].ID)
+	}
+	return ids, nil
+}
+
+func (p *gitlabProvider) findMergeRequest(ctx context.Context, head string) (int, error) {
+	var out []struct {
+		IID int `json:"iid"`
+	}
+	path := fmt.Sprintf("/projects/%s/merge_requests?state=opened&source_branch=%s", p.project, url.QueryEscape(head))
+	if err := p.get(ctx, path, &out); err != nil {
+		return 0, err
+	}
+	if len(out) == 0 {
+		return 0, nil
+	}
+	return out[0].IID, nil
+}
+
+func (p *gitlabProvider) get(ctx context.Context, path string, out interface{}) error {
+	return p.do(ctx, http.MethodGet, path, nil, out)
+}
+
+func (p *gitlabProvider) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return p.do(ctx, http.MethodPost, path, body, out)
+}
+
+func (p *gitlabProvider) put(ctx context.Context, path string, body interface{}, out interface{}) error {
+	return p.do(ctx, http.MethodPut, path, body, out)
+}
+
+func (p *gitlabProvider) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	return restDo(ctx, p.http, method, p.baseURL+"/api/v4"+path, "PRIVATE-TOKEN", p.cfg.Token, body, out)
+}
@@ -0,0 +1,195 @@
+// Package provider abstracts the forge-specific operations (GitHub, GitLab,
+// Gitea, Bitbucket Server) needed to push a commit and, optionally, open a
+// pull request for it.
+package provider
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"golang.org/x/oauth2"
+)
+
+// FileChange describes a single file addition (or modification) within a
+// commit. A rename surfaces as a FileChange for the new path plus a
+// Deletion for the old one; forges have no separate "rename" primitive.
+type FileChange struct {
+	Path     string
+	Contents []byte
+}
+
+// CommitRequest carries everything a Provider needs to create a single
+// commit on a branch.
+type CommitRequest struct {
+	Branch          string
+	Message         string
+	Changes         []FileChange
+	Deletions       []string
+	ExpectedHeadOID string
+}
+
+// CommitResult is returned after a commit has been created.
+type CommitResult struct {
+	OID string
+	URL string
+}
+
+// PullRequestRequest carries everything needed to open (or reuse) a pull
+// request / merge request for a branch. If a pull request for Head is
+// already open, OpenPullRequest updates it in place (retargeting it at
+// Base) rather than failing.
+type PullRequestRequest struct {
+	Base      string
+	Head      string
+	Title     string
+	Body      string
+	Draft     bool
+	Labels    []string
+	Reviewers []string
+	Assignees []string
+}
+
+// Provider is implemented once per forge kind (GitHub, GitLab, Gitea,
+// Bitbucket Server). It covers the operations the CLI needs: finding the tip
+// of a branch, making sure the branch exists, committing to it, and opening
+// a pull request.
+type Provider interface {
+	// HeadOID returns the current commit OID of branch, or an empty string
+	// if the branch does not exist.
+	HeadOID(ctx context.Context, branch string) (string, error)
+
+	// DefaultBranch returns the repository's configured default branch
+	// (e.g. "main", "master"), used when --base-branch isn't given.
+	DefaultBranch(ctx context.Context) (string, error)
+
+	// EnsureBranch creates branch pointing at oid if it does not already
+	// exist. It is a no-op if the branch is already present.
+	EnsureBranch(ctx context.Context, branch string, oid string) error
+
+	// Commit creates a new commit on req.Branch.
+	Commit(ctx context.Context, req CommitRequest) (CommitResult, error)
+
+	// OpenPullRequest opens a pull request for req.Head against req.Base,
+	// or updates the existing open one for req.Head if there is one.
+	OpenPullRequest(ctx context.Context, req PullRequestRequest) error
+}
+
+// Kind identifies which forge a Provider talks to.
+type Kind string
+
+const (
+	GitHub          Kind = "github"
+	GitLab          Kind = "gitlab"
+	Gitea           Kind = "gitea"
+	BitbucketServer Kind = "bitbucket"
+)
+
+// Config bundles the settings needed to construct any Provider. Repository
+// is always "owner/name"; BaseURL is only needed for self-hosted instances
+// and is left empty to use the forge's public API.
+//
+// GitRepo, SigningKeyPath, AuthorName and AuthorEmail are only consulted by
+// providers that fall back to a local go-git push (GitLab, Gitea, Bitbucket
+// Server), since those forges don't offer GitHub's server-side verified
+// createCommitOnBranch mutation.
+type Config struct {
+	Kind       Kind
+	Repository string
+	Token      string
+	BaseURL    string
+
+	// TokenSource, if set, takes priority over Token for the GitHub
+	// provider — it's how GitHub App installation-token auth plugs in,
+	// since that token refreshes and expires rather than being static.
+	// The REST-based providers (GitLab, Gitea, Bitbucket Server) don't
+	// consult it; they only support Token.
+	TokenSource oauth2.TokenSource
+
+	GitRepo        *git.Repository
+	SigningKeyPath string
+	AuthorName     string
+	AuthorEmail    string
+
+	// HTTPClient is used for the REST-based providers (GitLab, Gitea,
+	// Bitbucket Server); the GitHub provider wraps it in its own OAuth2
+	// transport instead. Defaults to http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Logger receives the same progress lines this package used to send
+	// straight to the log package, so library callers can route them
+	// wherever they like. Defaults to log.Default().
+	Logger *log.Logger
+}
+
+// logger returns cfg.Logger, or log.Default() if it wasn't set.
+func (cfg Config) logger() *log.Logger {
+	if cfg.Logger != nil {
+		return cfg.Logger
+	}
+	return log.Default()
+}
+
+// httpClient returns cfg.HTTPClient, or http.DefaultClient if it wasn't set.
+func (cfg Config) httpClient() *http.Client {
+	if cfg.HTTPClient != nil {
+		return cfg.HTTPClient
+	}
+	return http.DefaultClient
+}
+
+// New constructs the Provider for cfg.Kind.
+func New(ctx context.Context, cfg Config) (Provider, error) {
+	switch cfg.Kind {
+	case GitHub, "":
+		return newGitHubProvider(ctx, cfg)
+	case GitLab:
+		return newGitLabProvider(ctx, cfg)
+	case Gitea:
+		return newGiteaProvider(ctx, cfg)
+	case BitbucketServer:
+		return newBitbucketProvider(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown provider %q", cfg.Kind)
+	}
+}
+
+// DetectKind guesses the forge kind from a git remote URL, e.g. the
+// "origin" remote. It falls back to GitHub when the host isn't recognised,
+// since that's this tool's original and most common target.
+func DetectKind(remoteURL string) Kind {
+	host := remoteURL
+	if u, err := url.Parse(normalizeRemoteURL(remoteURL)); err == nil && u.Host != "" {
+		host = u.Host
+	}
+	host = strings.ToLower(host)
+
+	switch {
+	case strings.Contains(host, "gitlab"):
+		return GitLab
+	case strings.Contains(host, "gitea"):
+		return Gitea
+	case strings.Contains(host, "bitbucket"):
+		return BitbucketServer
+	default:
+		return GitHub
+	}
+}
+
+// normalizeRemoteURL turns scp-like git remotes (git@host:owner/repo.git)
+// into something net/url can parse.
+func normalizeRemoteURL(remote string) string {
+	if strings.Contains(remote, "://") {
+		return remote
+	}
+	if at := strings.Index(remote, "@"); at != -1 {
+		rest := remote[at+1:]
+		rest = strings.Replace(rest, ":", "/", 1)
+		return "ssh://" + rest
+	}
+	return remote
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrStaleHead is returned (wrapped) by a Provider's Commit method when the
+// commit was rejected because req.ExpectedHeadOID no longer matches the
+// branch's tip — i.e. somebody else pushed in the meantime. Callers that
+// want their own conflict-resolution strategy can check for it with
+// errors.Is; RetryCommit implements the default one.
+var ErrStaleHead = errors.New("expected head oid is stale")
+
+// RetryCommit calls p.Commit(ctx, req), and on ErrStaleHead re-fetches the
+// branch tip, asks rebuild to produce a fresh CommitRequest against it (so
+// any concurrently-updated file is picked up rather than silently
+// overwritten), and retries with exponential backoff and jitter. It gives up
+// after maxRetries retries (maxRetries+1 attempts in all) and returns the
+// last error.
+func RetryCommit(ctx context.Context, p Provider, req CommitRequest, maxRetries int, rebuild func(expectedHeadOID string) (CommitRequest, error)) (CommitResult, error) {
+	for attempt := 0; ; attempt++ {
+		result, err := p.Commit(ctx, req)
+		if err == nil {
+			return result, nil
+		}
+		if !errors.Is(err, ErrStaleHead) || attempt >= maxRetries {
+			return CommitResult{}, err
+		}
+
+		tip, headErr := p.HeadOID(ctx, req.Branch)
+		if headErr != nil {
+			return CommitResult{}, headErr
+		}
+		req, err = rebuild(tip)
+		if err != nil {
+			return CommitResult{}, err
+		}
+
+		time.Sleep(retryBackoff(attempt))
+	}
+}
+
+// retryBackoff returns an exponentially growing delay (200ms, 400ms, 800ms,
+// ...) with up to 50% jitter, so concurrent retries don't all collide
+// again on the next attempt.
+func retryBackoff(attempt int) time.Duration {
+	base := 200 * time.Millisecond * time.Duration(1<<attempt)
+	jitter := time.Duration(rand.Int63n(int64(base) / 2))
+	return base + jitter
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryBackoff(t *testing.T) {
+	for attempt := 0; attempt < 5; attempt++ {
+		base := 200 * time.Millisecond * time.Duration(1<<attempt)
+		for i := 0; i < 10; i++ {
+			d := retryBackoff(attempt)
+			if d < base || d >= base+base/2 {
+				t.Fatalf("attempt %d: retryBackoff() = %s, want in [%s, %s)", attempt, d, base, base+base/2)
+			}
+		}
+	}
+}
+
+// fakeProvider fails every Commit with ErrStaleHead until its call count
+// passes succeedOn, recording the ExpectedHeadOID it was called with.
+type fakeProvider struct {
+	Provider
+	succeedOn int
+	calls     int
+	seenOIDs  []string
+	headOID   string
+}
+
+func (f *fakeProvider) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	f.calls++
+	f.seenOIDs = append(f.seenOIDs, req.ExpectedHeadOID)
+	if f.calls <= f.succeedOn {
+		return CommitResult{}, ErrStaleHead
+	}
+	return CommitResult{OID: "done"}, nil
+}
+
+func (f *fakeProvider) HeadOID(ctx context.Context, branch string) (string, error) {
+	return f.headOID, nil
+}
+
+func TestRetryCommitSucceedsAfterRebuild(t *testing.T) {
+	prov := &fakeProvider{succeedOn: 2, headOID: "fresh-oid"}
+	req := CommitRequest{Branch: "main", ExpectedHeadOID: "stale-oid"}
+
+	var rebuiltWith []string
+	result, err := RetryCommit(context.Background(), prov, req, 3, func(tip string) (CommitRequest, error) {
+		rebuiltWith = append(rebuiltWith, tip)
+		req.ExpectedHeadOID = tip
+		return req, nil
+	})
+	if err != nil {
+		t.Fatalf("RetryCommit() error = %v, want nil", err)
+	}
+	if result.OID != "done" {
+		t.Fatalf("RetryCommit() result = %+v, want OID \"done\"", result)
+	}
+	if prov.calls != 3 {
+		t.Fatalf("Commit called %d times, want 3", prov.calls)
+	}
+	for _, tip := range rebuiltWith {
+		if tip != "fresh-oid" {
+			t.Fatalf("rebuild called with %q, want %q", tip, "fresh-oid")
+		}
+	}
+}
+
+func TestRetryCommitGivesUpAfterMaxRetries(t *testing.T) {
+	prov := &fakeProvider{succeedOn: 100, headOID: "fresh-oid"}
+	req := CommitRequest{Branch: "main", ExpectedHeadOID: "stale-oid"}
+
+	_, err := RetryCommit(context.Background(), prov, req, 2, func(tip string) (CommitRequest, error) {
+		req.ExpectedHeadOID = tip
+		return req, nil
+	})
+	if !errors.Is(err, ErrStaleHead) {
+		t.Fatalf("RetryCommit() error = %v, want ErrStaleHead", err)
+	}
+	if prov.calls != 3 {
+		t.Fatalf("Commit called %d times, want 3 (1 initial + 2 retries)", prov.calls)
+	}
+}
+
+func TestRetryCommitNonStaleErrorIsNotRetried(t *testing.T) {
+	boom := errors.New("boom")
+	prov := &stubProvider{commitErr: boom}
+	req := CommitRequest{Branch: "main", ExpectedHeadOID: "stale-oid"}
+
+	_, err := RetryCommit(context.Background(), prov, req, 3, func(tip string) (CommitRequest, error) {
+		t.Fatal("rebuild should not be called for a non-stale-head error")
+		return req, nil
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("RetryCommit() error = %v, want %v", err, boom)
+	}
+	if prov.calls != 1 {
+		t.Fatalf("Commit called %d times, want 1", prov.calls)
+	}
+}
+
+type stubProvider struct {
+	Provider
+	commitErr error
+	calls     int
+}
+
+func (s *stubProvider) Commit(ctx context.Context, req CommitRequest) (CommitResult, error) {
+	s.calls++
+	return CommitResult{}, s.commitErr
+}
@@ -0,0 +1,249 @@
+package provider
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// basicAuth builds the HTTP basic-auth credential used to push to GitLab,
+// Gitea and Bitbucket Server: the token goes in the password slot, the
+// username is ignored by all three as long as it's non-empty.
+func basicAuth(token string) *githttp.BasicAuth {
+	return &githttp.BasicAuth{Username: "github-committer", Password: token}
+}
+
+// signedPush builds a tree on top of parentOID's tree containing changes,
+// commits it with a GPG signature from the key at cfg.SigningKeyPath, and
+// pushes the result to branch on the "origin" remote using an HTTP
+// basic-auth credential of cfg.Token. It returns the new commit's OID.
+//
+// GitLab, Gitea and Bitbucket Server don't expose an equivalent of GitHub's
+// createCommitOnBranch, so unlike the GitHub provider this is how they all
+// produce a commit: a locally signed commit object, pushed over the wire.
+//
+// The new tree is derived directly from parentOID's own tree (read from
+// repo's object store) rather than from the local worktree/index, and the
+// commit object is written straight to repo's object store instead of via
+// Worktree.Commit: parentOID is the remote branch's tip, which generally
+// isn't anything the local checkout has ever had checked out, so building
+// off the local worktree would silently mix in unrelated local file state
+// and, via Worktree.Commit's updateHEAD side effect, move the local
+// repository's current branch to a commit it never asked for.
+func signedPush(repo *git.Repository, cfg Config, branch, message, parentOID string, changes []FileChange, deletions []string) (string, error) {
+	entity, err := loadSigningEntity(cfg.SigningKeyPath)
+	if err != nil {
+		return "", fmt.Errorf("loading signing key: %w", err)
+	}
+
+	var parents []plumbing.Hash
+	var parentTree *object.Tree
+	if parentOID != "" {
+		parentHash := plumbing.NewHash(parentOID)
+		parentCommit, err := repo.CommitObject(parentHash)
+		if err != nil {
+			return "", fmt.Errorf("reading parent commit %s: %w", parentOID, err)
+		}
+		parentTree, err = parentCommit.Tree()
+		if err != nil {
+			return "", fmt.Errorf("reading parent tree: %w", err)
+		}
+		parents = []plumbing.Hash{parentHash}
+	}
+
+	edits := make(map[string][]byte, len(changes)+len(deletions))
+	for _, change := range changes {
+		edits[change.Path] = change.Contents
+	}
+	for _, path := range deletions {
+		edits[path] = nil
+	}
+
+	treeHash, err := applyTreeEdits(repo, parentTree, edits)
+	if err != nil {
+		return "", fmt.Errorf("building commit tree: %w", err)
+	}
+
+	sig := object.Signature{Name: cfg.AuthorName, Email: cfg.AuthorEmail, When: time.Now()}
+	commit := &object.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      message,
+		TreeHash:     treeHash,
+		ParentHashes: parents,
+	}
+	commit.PGPSignature, err = signCommit(repo, commit, entity)
+	if err != nil {
+		return "", fmt.Errorf("signing commit: %w", err)
+	}
+
+	oid, err := storeObject(repo, commit)
+	if err != nil {
+		return "", fmt.Errorf("storing commit: %w", err)
+	}
+
+	refSpec := config.RefSpec(fmt.Sprintf("%s:refs/heads/%s", oid.String(), branch))
+	err = repo.Push(&git.PushOptions{
+		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{refSpec},
+		Auth:       basicAuth(cfg.Token),
+	})
+	if err != nil {
+		if isNonFastForwardError(err) {
+			return "", fmt.Errorf("%w: %s", ErrStaleHead, err)
+		}
+		return "", fmt.Errorf("pushing %s: %w", branch, err)
+	}
+
+	return oid.String(), nil
+}
+
+// applyTreeEdits builds (and stores) a new tree rooted like base, with
+// edits applied on top of it; a nil value in edits deletes that path. base
+// may be nil, meaning an empty starting tree. Only the branches containing
+// an edit are rewritten — everything else is copied over from base by
+// reference, so the remote branch only ever receives the intended delta.
+func applyTreeEdits(repo *git.Repository, base *object.Tree, edits map[string][]byte) (plumbing.Hash, error) {
+	entries := map[string]object.TreeEntry{}
+	if base != nil {
+		for _, entry := range base.Entries {
+			entries[entry.Name] = entry
+		}
+	}
+
+	subEdits := map[string]map[string][]byte{}
+	for path, content := range edits {
+		name, rest, nested := strings.Cut(path, "/")
+		if !nested {
+			if content == nil {
+				delete(entries, name)
+				continue
+			}
+			blobHash, err := storeBlob(repo, content)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			entries[name] = object.TreeEntry{Name: name, Mode: filemode.Regular, Hash: blobHash}
+			continue
+		}
+		if subEdits[name] == nil {
+			subEdits[name] = map[string][]byte{}
+		}
+		subEdits[name][rest] = content
+	}
+
+	for name, nested := range subEdits {
+		var subtree *object.Tree
+		if entry, ok := entries[name]; ok && entry.Mode == filemode.Dir {
+			t, err := object.GetTree(repo.Storer, entry.Hash)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			subtree = t
+		}
+		hash, err := applyTreeEdits(repo, subtree, nested)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries[name] = object.TreeEntry{Name: name, Mode: filemode.Dir, Hash: hash}
+	}
+
+	sorted := make([]object.TreeEntry, 0, len(entries))
+	for _, entry := range entries {
+		sorted = append(sorted, entry)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	return storeObject(repo, &object.Tree{Entries: sorted})
+}
+
+// storeBlob writes content as a new blob object and returns its hash.
+func storeBlob(repo *git.Repository, content []byte) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := w.Write(content); err != nil {
+		w.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := w.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// storeObject encodes o (an *object.Tree or *object.Commit) and writes it
+// to repo's object store, returning its hash.
+func storeObject(repo *git.Repository, o interface {
+	Encode(plumbing.EncodedObject) error
+}) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	if err := o.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// signCommit produces the detached-armored PGP signature go-git's
+// Worktree.Commit would have attached for the same SignKey, computed over
+// commit's canonical encoding. It doesn't touch repo; it's only a parameter
+// because commit objects need a Storer-backed EncodedObject to encode into.
+func signCommit(repo *git.Repository, commit *object.Commit, signKey *openpgp.Entity) (string, error) {
+	encoded := repo.Storer.NewEncodedObject()
+	if err := commit.Encode(encoded); err != nil {
+		return "", err
+	}
+	r, err := encoded.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+
+	var buf bytes.Buffer
+	if err := openpgp.ArmoredDetachSign(&buf, signKey, r, nil); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// isNonFastForwardError recognizes a rejected push whose parent no longer
+// matches the remote tip — the go-git equivalent of GitHub's stale
+// ExpectedHeadOid.
+func isNonFastForwardError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "non-fast-forward") || strings.Contains(msg, "fetch first")
+}
+
+func loadSigningEntity(path string) (*openpgp.Entity, error) {
+	if path == "" {
+		return nil, fmt.Errorf("no GPG signing key configured (set --signing-key)")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entityList, err := openpgp.ReadArmoredKeyRing(f)
+	if err != nil {
+		return nil, err
+	}
+	if len(entityList) == 0 {
+		return nil, fmt.Errorf("%s contains no PGP keys", path)
+	}
+	return entityList[0], nil
+}